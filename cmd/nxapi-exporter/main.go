@@ -0,0 +1,82 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command nxapi-exporter runs an HTTP server that exposes Prometheus
+// metrics scraped from a single Cisco NX-OS device's NX-API endpoint.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/paultyng/go-cisco-nx-api/pkg/client"
+	"github.com/paultyng/go-cisco-nx-api/pkg/client/exporter"
+)
+
+func main() {
+	var (
+		host     = flag.String("host", "", "NX-API device hostname or IP address")
+		port     = flag.Int("port", 443, "NX-API device port")
+		protocol = flag.String("protocol", "https", "NX-API protocol (http or https)")
+		username = flag.String("username", "admin", "NX-API username")
+		password = flag.String("password", "", "NX-API password")
+		insecure = flag.Bool("insecure", false, "skip TLS certificate verification")
+
+		listenAddr     = flag.String("listen-address", ":9414", "address to serve /metrics on")
+		metricsPath    = flag.String("metrics-path", "/metrics", "path to serve /metrics on")
+		scrapeInterval = flag.Duration("scrape-interval", 0, "if set, poll the device in the background on this interval and serve cached metrics instead of querying it on every scrape")
+		disableSystem  = flag.Bool("disable-system-resources", false, "disable the show system resources collector")
+		disableEnv     = flag.Bool("disable-environment", false, "disable the show environment collector")
+		disableIfaces  = flag.Bool("disable-interfaces", false, "disable the show interface collector")
+		disableXcvr    = flag.Bool("disable-transceivers", false, "disable the show interface transceiver details collector")
+		disableBgp     = flag.Bool("disable-bgp-summary", false, "disable the show ip bgp summary vrf all collector")
+	)
+	flag.Parse()
+
+	if *host == "" {
+		log.Fatal("nxapi-exporter: -host is required")
+	}
+
+	cli := client.NewClient()
+	cli.SetHost(*host)
+	cli.SetPort(*port)
+	cli.SetProtocol(*protocol)
+	cli.SetUsername(*username)
+	cli.SetPassword(*password)
+	cli.SetInsecure(*insecure)
+
+	exp := exporter.NewExporter(cli)
+	enabled := exp.Enable()
+	enabled.SystemResources = !*disableSystem
+	enabled.SystemEnvironment = !*disableEnv
+	enabled.Interfaces = !*disableIfaces
+	enabled.Transceivers = !*disableXcvr
+	enabled.BgpSummary = !*disableBgp
+
+	var collector prometheus.Collector = exp
+	if *scrapeInterval > 0 {
+		collector = exporter.NewCachingExporter(exp, *scrapeInterval)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	http.Handle(*metricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	log.Printf("nxapi-exporter: serving %s on %s for device %s", *metricsPath, *listenAddr, *host)
+	log.Fatal(http.ListenAndServe(*listenAddr, nil))
+}
@@ -0,0 +1,175 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// BatchResult is the outcome of a single command within a RunBatch call.
+type BatchResult struct {
+	ID      int
+	Command string
+	Raw     json.RawMessage
+	Result  interface{}
+	Err     error
+}
+
+// batchDispatch maps a show command onto a decoder that turns its raw
+// JSON-RPC result into the same typed value GetInterfaces, GetVlans,
+// etc. would return on their own. Commands without a known parser are
+// still returned in the batch, with Result left nil and Raw populated.
+var batchDispatch = map[string]func(json.RawMessage) (interface{}, error){
+	"show version": func(raw json.RawMessage) (interface{}, error) {
+		info := &SystemInfo{}
+		if err := json.Unmarshal(raw, info); err != nil {
+			return nil, err
+		}
+		return info, nil
+	},
+	"show interface": func(raw json.RawMessage) (interface{}, error) {
+		body := &interfaceBody{}
+		if err := json.Unmarshal(raw, body); err != nil {
+			return nil, err
+		}
+		var ifaces []Interface
+		for _, table := range body.TableInterface {
+			ifaces = append(ifaces, table.RowInterface...)
+		}
+		return ifaces, nil
+	},
+	"show vlan": func(raw json.RawMessage) (interface{}, error) {
+		body := &vlanBody{}
+		if err := json.Unmarshal(raw, body); err != nil {
+			return nil, err
+		}
+		var vlans []Vlan
+		for _, table := range body.TableVlanbrief {
+			vlans = append(vlans, table.RowVlanbrief...)
+		}
+		return vlans, nil
+	},
+}
+
+// RunBatch issues every command in cmds as a single JSON-RPC batch POST
+// to /ins and returns one BatchResult per command, in the same order as
+// cmds. Responses are matched back to their command by JSON-RPC request
+// ID rather than by position, since a device is free to return batch
+// results in any order. A per-command failure (a JSON-RPC error object,
+// or a parser error for a known command) is reported on that command's
+// BatchResult alone; RunBatch's own error return is reserved for
+// failures that prevent the batch as a whole from completing, such as a
+// transport error or a malformed response envelope.
+func (cli *Client) RunBatch(cmds []string) ([]BatchResult, error) {
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+
+	reqs := make([]*JSONRPCRequest, len(cmds))
+	for i, cmd := range cmds {
+		id := int(atomic.AddInt64(&cli.requestID, 1))
+		reqs[i] = &JSONRPCRequest{
+			Jsonrpc: "2.0",
+			Method:  "cli",
+			Params:  JSONRPCParams{Command: cmd, Version: 1},
+			ID:      id,
+		}
+	}
+
+	start := time.Now()
+	payload, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to marshal batch of %d command(s): %s", len(cmds), err)
+	}
+
+	log := cli.log.With("host", cli.host, "batch_size", len(cmds), "payload_bytes", len(payload))
+
+	httpReq, err := http.NewRequest("POST", cli.url(), bytes.NewReader(payload))
+	if err != nil {
+		log.Error("failed to build batch request", "error", err)
+		return nil, fmt.Errorf("client: failed to build batch request: %s", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.SetBasicAuth(cli.username, cli.password)
+
+	resp, err := cli.httpClient.Do(httpReq)
+	if err != nil {
+		log.Error("batch request failed", "error", err, "duration_ms", time.Since(start).Milliseconds())
+		return nil, fmt.Errorf("client: batch request failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("failed to read batch response body", "error", err, "http_status", resp.StatusCode)
+		return nil, fmt.Errorf("client: failed to read batch response: %s", err)
+	}
+
+	duration := time.Since(start)
+	if resp.StatusCode != http.StatusOK {
+		log.Error("non-200 batch response", "http_status", resp.StatusCode, "duration_ms", duration.Milliseconds())
+		return nil, fmt.Errorf("client: batch request returned HTTP %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var rpcResults []JSONRPCResponse
+	if err := json.Unmarshal(respBody, &rpcResults); err != nil {
+		log.Error("failed to unmarshal batch response", "error", err, "duration_ms", duration.Milliseconds())
+		return nil, fmt.Errorf("client: failed to unmarshal batch response: %s", err)
+	}
+	if len(rpcResults) != len(cmds) {
+		log.Error("batch response length mismatch", "got", len(rpcResults), "want", len(cmds), "duration_ms", duration.Milliseconds())
+		return nil, fmt.Errorf("client: batch request for %d command(s) got %d result(s)", len(cmds), len(rpcResults))
+	}
+
+	log.Info("batch completed", "http_status", resp.StatusCode, "duration_ms", duration.Milliseconds())
+
+	resultByID := make(map[int]JSONRPCResponse, len(rpcResults))
+	for _, rpcResult := range rpcResults {
+		resultByID[rpcResult.ID] = rpcResult
+	}
+
+	results := make([]BatchResult, len(cmds))
+	for i, req := range reqs {
+		cmd := cmds[i]
+		rpcResult, ok := resultByID[req.ID]
+		if !ok {
+			results[i] = BatchResult{ID: req.ID, Command: cmd, Err: fmt.Errorf("client: no batch result for command %q (request id %d)", cmd, req.ID)}
+			continue
+		}
+
+		br := BatchResult{ID: rpcResult.ID, Command: cmd, Raw: rpcResult.Result}
+		if rpcResult.Error != nil {
+			br.Err = fmt.Errorf("client: command %q failed: %s", cmd, rpcResult.Error.Message)
+			results[i] = br
+			continue
+		}
+		if decode, known := batchDispatch[cmd]; known {
+			value, err := decode(rpcResult.Result)
+			if err != nil {
+				br.Err = fmt.Errorf("client: failed to parse result for %q: %s", cmd, err)
+			} else {
+				br.Result = value
+			}
+		}
+		results[i] = br
+	}
+	return results, nil
+}
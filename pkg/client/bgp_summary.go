@@ -0,0 +1,32 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// BgpSummary is the parsed result of "show ip bgp summary vrf all". Like
+// RunningConfiguration, the device returns this as ASCII text rather than
+// a structured table.
+type BgpSummary struct {
+	Text string
+}
+
+// GetBgpSummary issues "show ip bgp summary vrf all" and returns the raw
+// output text.
+func (cli *Client) GetBgpSummary() (*BgpSummary, error) {
+	text, err := cli.getTextOutput("show ip bgp summary vrf all")
+	if err != nil {
+		return nil, err
+	}
+	return &BgpSummary{Text: text}, nil
+}
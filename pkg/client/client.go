@@ -0,0 +1,150 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Client holds the state necessary to talk to a Cisco NX-OS device. By
+// default it speaks the NX-API HTTP/JSON endpoint; pass WithTransport to
+// NewClient to use a different Transport (e.g. the gnmi package's
+// Transport) instead.
+type Client struct {
+	protocol string
+	host     string
+	port     int
+	username string
+	password string
+	timeout  time.Duration
+	insecure bool
+
+	httpClient *http.Client
+	transport  Transport
+	log        Logger
+
+	// requestID is incremented with atomic.AddInt64 rather than a plain
+	// field, since a single Client is routinely shared across goroutines
+	// (e.g. one per subscribed command in the collector package).
+	requestID int64
+}
+
+// NewClient returns a Client configured with sane defaults and the
+// built-in NX-API HTTP/JSON transport. Callers use the Set* methods to
+// point it at a real device before issuing requests, and Options to
+// customize construction-time behavior such as the Transport.
+func NewClient(opts ...Option) *Client {
+	cli := &Client{
+		protocol: "http",
+		port:     80,
+		timeout:  10 * time.Second,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		log: newNopLogger(),
+	}
+	cli.transport = &restTransport{cli: cli}
+	for _, opt := range opts {
+		opt(cli)
+	}
+	return cli
+}
+
+// NewClientWithLogger returns a Client identical to NewClient but with the
+// given Logger already installed via SetLogger, so that every request it
+// issues is logged through it.
+func NewClientWithLogger(logger Logger) *Client {
+	cli := NewClient()
+	cli.SetLogger(logger)
+	return cli
+}
+
+// SetLogger swaps the Client's Logger. Passing nil restores the no-op
+// logger so callers never need to nil-check before logging.
+func (cli *Client) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = newNopLogger()
+	}
+	cli.log = logger
+}
+
+// Logger returns the Client's current Logger, so that other packages
+// built on top of Client (e.g. collector) can share it instead of
+// requiring callers to wire up their own.
+func (cli *Client) Logger() Logger {
+	return cli.log
+}
+
+// SetHost sets the hostname or IP address of the target device.
+func (cli *Client) SetHost(host string) {
+	cli.host = host
+}
+
+// SetPort sets the TCP port the NX-API endpoint listens on.
+func (cli *Client) SetPort(port int) {
+	cli.port = port
+}
+
+// SetProtocol sets the scheme ("http" or "https") used to reach the
+// NX-API endpoint.
+func (cli *Client) SetProtocol(protocol string) {
+	cli.protocol = protocol
+}
+
+// SetUsername sets the username used for HTTP basic authentication.
+func (cli *Client) SetUsername(username string) {
+	cli.username = username
+}
+
+// SetPassword sets the password used for HTTP basic authentication.
+func (cli *Client) SetPassword(password string) {
+	cli.password = password
+}
+
+// SetTimeout overrides the default per-request HTTP timeout.
+func (cli *Client) SetTimeout(timeout time.Duration) {
+	cli.timeout = timeout
+	cli.httpClient.Timeout = timeout
+}
+
+// SetInsecure disables TLS certificate verification. Only useful when
+// talking to devices with self-signed certificates in lab environments.
+func (cli *Client) SetInsecure(insecure bool) {
+	cli.insecure = insecure
+	cli.httpClient.Transport = &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+	}
+}
+
+func (cli *Client) url() string {
+	return fmt.Sprintf("%s://%s:%d/ins", cli.protocol, cli.host, cli.port)
+}
+
+// run fetches a single show command through the Client's Transport and
+// unmarshals the result into v.
+func (cli *Client) run(cmd string, v interface{}) error {
+	body, err := cli.transport.Execute(cmd)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(body, v)
+}
@@ -27,13 +27,58 @@ import (
 	"time"
 )
 
+// fixturePathForCmd maps a show command onto its ins_api fixture file
+// under assets/requests. It is shared by the command-dispatching fake
+// servers in this package so per-command fixture lookup stays in one
+// place.
+func fixturePathForCmd(cmd string) (string, bool) {
+	dataDir := "../../assets/requests"
+	switch cmd {
+	case "show version":
+		return fmt.Sprintf("%s/%s", dataDir, "resp.show.version.1.json"), true
+	case "show vlan":
+		return fmt.Sprintf("%s/%s", dataDir, "resp.show.vlans.2.json"), true
+	case "show interface":
+		return fmt.Sprintf("%s/%s", dataDir, "resp.show.interfaces.4.json"), true
+	case "show system resources":
+		return fmt.Sprintf("%s/%s", dataDir, "resp.show.system.resources.1.json"), true
+	case "show environment":
+		return fmt.Sprintf("%s/%s", dataDir, "resp.show.environment.1.json"), true
+	case "show running-config":
+		return fmt.Sprintf("%s/%s", dataDir, "resp.show.running.config.1.json"), true
+	case "show ip bgp summary vrf all":
+		return fmt.Sprintf("%s/%s", dataDir, "resp.show.ip.bgp.summary.vrf.all.1.json"), true
+	case "show interface transceiver details":
+		return fmt.Sprintf("%s/%s", dataDir, "resp.show.interface.transceiver.details.1.json"), true
+	default:
+		return "", false
+	}
+}
+
+// fixtureBodyForCmd reads cmd's ins_api fixture and returns just its
+// output body, for reuse in a JSON-RPC batch response.
+func fixtureBodyForCmd(cmd string) (json.RawMessage, bool, error) {
+	fp, ok := fixturePathForCmd(cmd)
+	if !ok {
+		return nil, false, nil
+	}
+	fc, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, true, err
+	}
+	var envelope InsAPIResponse
+	if err := json.Unmarshal(fc, &envelope); err != nil {
+		return nil, true, err
+	}
+	return envelope.InsAPI.Outputs.Output.Body, true, nil
+}
+
 func TestClient(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
 		var err error
 		var fp string
 		var fc []byte
-		dataDir := "../../assets/requests"
 		if req.Method != "POST" {
 			http.Error(w, "Bad Request, expecting POST", http.StatusBadRequest)
 			return
@@ -53,10 +98,8 @@ func TestClient(t *testing.T) {
 				http.Error(w, fmt.Sprintf("Bad Request, json.Unmarshal: %s", err), http.StatusBadRequest)
 				return
 			}
-			if len(j) != 1 {
-				http.Error(w, fmt.Sprintf("Bad Request, expecting a single query, got %d", len(j)), http.StatusBadRequest)
-			}
-			cmd = j[0].Params.Command
+			writeBatchResponse(w, j)
+			return
 		} else if bytes.Contains(body, []byte(`"ins_api":`)) {
 			var j *InsAPIRequest
 			err = json.Unmarshal(body, &j)
@@ -71,25 +114,11 @@ func TestClient(t *testing.T) {
 		}
 
 		t.Logf("server: received command: %s", cmd)
-		switch cmd {
-		case "show version":
-			fp = fmt.Sprintf("%s/%s", dataDir, "resp.show.version.1.json")
-		case "show vlan":
-			fp = fmt.Sprintf("%s/%s", dataDir, "resp.show.vlans.2.json")
-		case "show interface":
-			fp = fmt.Sprintf("%s/%s", dataDir, "resp.show.interfaces.4.json")
-		case "show system resources":
-			fp = fmt.Sprintf("%s/%s", dataDir, "resp.show.system.resources.1.json")
-		case "show environment":
-			fp = fmt.Sprintf("%s/%s", dataDir, "resp.show.environment.1.json")
-		case "show running-config":
-			fp = fmt.Sprintf("%s/%s", dataDir, "resp.show.running.config.1.json")
-		case "show ip bgp summary vrf all":
-			fp = fmt.Sprintf("%s/%s", dataDir, "resp.show.ip.bgp.summary.vrf.all.1.json")
-		case "show interface transceiver details":
-			fp = fmt.Sprintf("%s/%s", dataDir, "resp.show.interface.transceiver.details.1.json")
-		default:
+		var ok bool
+		fp, ok = fixturePathForCmd(cmd)
+		if !ok {
 			http.Error(w, fmt.Sprintf("Bad Request, unsupported command: %s", cmd), http.StatusBadRequest)
+			return
 		}
 		fc, err = ioutil.ReadFile(fp)
 		if err != nil {
@@ -182,3 +211,257 @@ func TestClient(t *testing.T) {
 	t.Logf("client: Transceivers: %d", len(transceivers))
 	t.Logf("client: took %s", time.Since(start))
 }
+
+// memoryLogEntry records a single call to a memoryLogger method.
+type memoryLogEntry struct {
+	level  string
+	msg    string
+	fields []interface{}
+}
+
+// memoryLogger is a client.Logger that appends every call to a slice
+// instead of emitting it anywhere, so tests can assert on what was
+// logged.
+type memoryLogger struct {
+	prefix  []interface{}
+	entries *[]memoryLogEntry
+}
+
+func newMemoryLogger() *memoryLogger {
+	return &memoryLogger{entries: &[]memoryLogEntry{}}
+}
+
+func (l *memoryLogger) record(level, msg string, fields ...interface{}) {
+	*l.entries = append(*l.entries, memoryLogEntry{
+		level:  level,
+		msg:    msg,
+		fields: append(append([]interface{}{}, l.prefix...), fields...),
+	})
+}
+
+func (l *memoryLogger) Debug(msg string, fields ...interface{}) { l.record("debug", msg, fields...) }
+func (l *memoryLogger) Info(msg string, fields ...interface{})  { l.record("info", msg, fields...) }
+func (l *memoryLogger) Error(msg string, fields ...interface{}) { l.record("error", msg, fields...) }
+
+func (l *memoryLogger) With(fields ...interface{}) Logger {
+	return &memoryLogger{
+		prefix:  append(append([]interface{}{}, l.prefix...), fields...),
+		entries: l.entries,
+	}
+}
+
+// writeBatchResponse fans out a JSON-RPC batch request, one fixture
+// lookup per entry, and writes back a same-length array of
+// JSONRPCResponse in request order. A command with no fixture (such as
+// "show bogus command") is reported as a JSON-RPC error for that entry
+// alone rather than failing the whole batch, so tests can exercise
+// RunBatch's partial-success semantics.
+func writeBatchResponse(w http.ResponseWriter, reqs []*JSONRPCRequest) {
+	results := make([]JSONRPCResponse, len(reqs))
+	for i, req := range reqs {
+		results[i] = JSONRPCResponse{ID: req.ID, Jsonrpc: "2.0"}
+		body, ok, err := fixtureBodyForCmd(req.Params.Command)
+		switch {
+		case err != nil:
+			results[i].Error = &JSONRPCError{Code: -32000, Message: err.Error()}
+		case !ok:
+			results[i].Error = &JSONRPCError{Code: -32601, Message: fmt.Sprintf("unsupported command: %s", req.Params.Command)}
+		default:
+			results[i].Result = body
+		}
+	}
+	fc, err := json.Marshal(results)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(fc)
+}
+
+func TestClientRunBatch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var j []*JSONRPCRequest
+		if err := json.Unmarshal(body, &j); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeBatchResponse(w, j)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	srv := strings.Split(server.URL, ":")
+	port, _ := strconv.Atoi(srv[2])
+
+	cli := NewClient()
+	cli.SetHost("127.0.0.1")
+	cli.SetPort(port)
+	cli.SetProtocol(srv[0])
+	cli.SetUsername("admin")
+	cli.SetPassword("cisco")
+
+	cmds := []string{"show version", "show bogus command", "show vlan"}
+	results, err := cli.RunBatch(cmds)
+	if err != nil {
+		t.Fatalf("client: RunBatch: %s", err)
+	}
+	if len(results) != len(cmds) {
+		t.Fatalf("client: RunBatch: got %d result(s), want %d", len(results), len(cmds))
+	}
+
+	for i, cmd := range cmds {
+		if results[i].Command != cmd {
+			t.Fatalf("client: RunBatch: result %d: got command %q, want %q (ordering not preserved)", i, results[i].Command, cmd)
+		}
+	}
+
+	if results[0].Err != nil {
+		t.Fatalf("client: RunBatch: expected \"show version\" to succeed, got %s", results[0].Err)
+	}
+	info, ok := results[0].Result.(*SystemInfo)
+	if !ok || info.Hostname == "" {
+		t.Fatalf("client: RunBatch: expected a parsed *SystemInfo for \"show version\", got %#v", results[0].Result)
+	}
+
+	if results[1].Err == nil {
+		t.Fatal("client: RunBatch: expected \"show bogus command\" to fail")
+	}
+
+	if results[2].Err != nil {
+		t.Fatalf("client: RunBatch: expected \"show vlan\" to succeed, got %s", results[2].Err)
+	}
+	vlans, ok := results[2].Result.([]Vlan)
+	if !ok || len(vlans) == 0 {
+		t.Fatalf("client: RunBatch: expected a parsed []Vlan for \"show vlan\", got %#v", results[2].Result)
+	}
+}
+
+// TestClientRunBatchOutOfOrderResponses checks that RunBatch pairs each
+// response with the command that produced it by JSON-RPC request ID,
+// not by its position in the response array, since nothing guarantees a
+// device returns batch results in request order.
+func TestClientRunBatchOutOfOrderResponses(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var j []*JSONRPCRequest
+		if err := json.Unmarshal(body, &j); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		results := make([]JSONRPCResponse, len(j))
+		for i, req := range j {
+			results[i] = JSONRPCResponse{ID: req.ID, Jsonrpc: "2.0"}
+			body, ok, err := fixtureBodyForCmd(req.Params.Command)
+			switch {
+			case err != nil:
+				results[i].Error = &JSONRPCError{Code: -32000, Message: err.Error()}
+			case !ok:
+				results[i].Error = &JSONRPCError{Code: -32601, Message: fmt.Sprintf("unsupported command: %s", req.Params.Command)}
+			default:
+				results[i].Result = body
+			}
+		}
+		// Reverse the response order so position no longer matches the
+		// request order the commands were sent in.
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+		fc, err := json.Marshal(results)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(fc)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	srv := strings.Split(server.URL, ":")
+	port, _ := strconv.Atoi(srv[2])
+
+	cli := NewClient()
+	cli.SetHost("127.0.0.1")
+	cli.SetPort(port)
+	cli.SetProtocol(srv[0])
+	cli.SetUsername("admin")
+	cli.SetPassword("cisco")
+
+	cmds := []string{"show version", "show vlan"}
+	results, err := cli.RunBatch(cmds)
+	if err != nil {
+		t.Fatalf("client: RunBatch: %s", err)
+	}
+
+	if results[0].Command != "show version" || results[0].Err != nil {
+		t.Fatalf("client: RunBatch: result 0: got %+v, want a successful \"show version\" result", results[0])
+	}
+	if _, ok := results[0].Result.(*SystemInfo); !ok {
+		t.Fatalf("client: RunBatch: result 0: expected a parsed *SystemInfo, got %#v", results[0].Result)
+	}
+
+	if results[1].Command != "show vlan" || results[1].Err != nil {
+		t.Fatalf("client: RunBatch: result 1: got %+v, want a successful \"show vlan\" result", results[1])
+	}
+	if _, ok := results[1].Result.([]Vlan); !ok {
+		t.Fatalf("client: RunBatch: result 1: expected a parsed []Vlan, got %#v", results[1].Result)
+	}
+}
+
+func TestClientStructuredLogging(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
+		fc, err := ioutil.ReadFile("../../assets/requests/resp.show.version.1.json")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(fc)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	srv := strings.Split(server.URL, ":")
+	port, _ := strconv.Atoi(srv[2])
+
+	logger := newMemoryLogger()
+	cli := NewClientWithLogger(logger)
+	cli.SetHost("127.0.0.1")
+	cli.SetPort(port)
+	cli.SetProtocol(srv[0])
+	cli.SetUsername("admin")
+	cli.SetPassword("cisco")
+
+	if _, err := cli.GetSystemInfo(); err != nil {
+		t.Fatalf("client: %s", err)
+	}
+
+	if len(*logger.entries) == 0 {
+		t.Fatal("client: expected at least one structured log event for GetSystemInfo")
+	}
+
+	found := false
+	for _, entry := range *logger.entries {
+		if entry.level != "info" {
+			continue
+		}
+		for i := 0; i+1 < len(entry.fields); i += 2 {
+			if entry.fields[i] == "cmd" && entry.fields[i+1] == "show version" {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Fatal("client: expected a logged event with cmd=\"show version\"")
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package collector periodically polls a set of show commands against a
+// client.Client and streams the parsed results to subscriber-provided
+// handlers. It builds entirely on the public client.Client API, so it
+// works against any host the client package can reach.
+package collector
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/paultyng/go-cisco-nx-api/pkg/client"
+)
+
+// Handler receives the parsed result of a single poll of a show command.
+// result holds one of the client package's Get* return types (e.g.
+// *client.SystemInfo, []client.Interface); err is non-nil when every
+// retry attempt for this poll was exhausted.
+type Handler func(cmd string, result interface{}, err error)
+
+// dispatch maps a show command onto the client.Client method that
+// fetches and parses it.
+var dispatch = map[string]func(*client.Client) (interface{}, error){
+	"show version": func(cli *client.Client) (interface{}, error) {
+		return cli.GetSystemInfo()
+	},
+	"show interface": func(cli *client.Client) (interface{}, error) {
+		return cli.GetInterfaces()
+	},
+	"show vlan": func(cli *client.Client) (interface{}, error) {
+		return cli.GetVlans()
+	},
+	"show system resources": func(cli *client.Client) (interface{}, error) {
+		return cli.GetSystemResources()
+	},
+	"show environment": func(cli *client.Client) (interface{}, error) {
+		return cli.GetSystemEnvironment()
+	},
+	"show interface transceiver details": func(cli *client.Client) (interface{}, error) {
+		return cli.GetTransceivers()
+	},
+	"show ip bgp summary vrf all": func(cli *client.Client) (interface{}, error) {
+		return cli.GetBgpSummary()
+	},
+}
+
+// subscription is one command's polling configuration.
+type subscription struct {
+	cmd      string
+	interval time.Duration
+	handler  Handler
+}
+
+// Collector periodically polls a fixed set of show commands and routes
+// each parsed result to its subscriber's Handler.
+type Collector struct {
+	cli *client.Client
+	log client.Logger
+
+	retryTimeout time.Duration
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+
+	mu    sync.Mutex
+	subs  []subscription
+	wg    sync.WaitGroup
+	stop  chan struct{}
+	ready bool
+}
+
+// Option configures a Collector at construction time.
+type Option func(*Collector)
+
+// WithRetryTimeout bounds how long a single poll's retry loop may run
+// before giving up, modelled on "retry-timeout"/"sleep" semantics: the
+// loop stops as soon as elapsed+sleep would exceed timeout. The default
+// is 30s.
+func WithRetryTimeout(timeout time.Duration) Option {
+	return func(c *Collector) { c.retryTimeout = timeout }
+}
+
+// WithBackoff overrides the base and max jittered exponential backoff
+// durations used between retry attempts. The default base is 500ms and
+// the default max is 30s.
+func WithBackoff(base, max time.Duration) Option {
+	return func(c *Collector) {
+		c.baseBackoff = base
+		c.maxBackoff = max
+	}
+}
+
+// WithLogger wires a client.Logger into the collector for pre/post
+// request logging. Defaults to cli's own logger.
+func WithLogger(logger client.Logger) Option {
+	return func(c *Collector) { c.log = logger }
+}
+
+// New returns a Collector that polls cli. Subscribe must be called
+// before Start to register commands.
+func New(cli *client.Client, opts ...Option) *Collector {
+	c := &Collector{
+		cli:          cli,
+		log:          cli.Logger(),
+		retryTimeout: 30 * time.Second,
+		baseBackoff:  500 * time.Millisecond,
+		maxBackoff:   30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Subscribe registers handler to be called with the parsed result of cmd
+// every interval, once Start is called. cmd must be one of the commands
+// the collector knows how to dispatch (see the package-level dispatch
+// table); unknown commands are reported to handler as an error on every
+// poll rather than rejected at Subscribe time, so subscriptions set up
+// before Start can still be adjusted by later package versions.
+func (c *Collector) Subscribe(cmd string, interval time.Duration, handler Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subs = append(c.subs, subscription{cmd: cmd, interval: interval, handler: handler})
+}
+
+// Start begins polling every subscribed command in its own goroutine.
+// Polling stops when ctx is cancelled or Stop is called, whichever comes
+// first. Start returns immediately; call Stop (or cancel ctx) to shut
+// the collector down.
+func (c *Collector) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.ready {
+		c.mu.Unlock()
+		return fmt.Errorf("collector: already started")
+	}
+	c.stop = make(chan struct{})
+	c.ready = true
+	subs := append([]subscription{}, c.subs...)
+	c.mu.Unlock()
+
+	for _, sub := range subs {
+		sub := sub
+		c.wg.Add(1)
+		go c.run(ctx, sub)
+	}
+	return nil
+}
+
+// Stop cancels every future poll and retry sleep started by Start and
+// blocks until all polling goroutines have exited. A request already in
+// progress is not interrupted: Stop waits for it to return, and its
+// handler is still called if it succeeds. It resets the
+// collector's shared cancel channel so a subsequent Start begins clean,
+// the same way net.Conn's SetReadDeadline/SetWriteDeadline are reset
+// for the next operation rather than latched permanently.
+func (c *Collector) Stop() {
+	c.mu.Lock()
+	if !c.ready {
+		c.mu.Unlock()
+		return
+	}
+	close(c.stop)
+	c.ready = false
+	c.mu.Unlock()
+
+	c.wg.Wait()
+}
+
+func (c *Collector) run(ctx context.Context, sub subscription) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(sub.interval)
+	defer ticker.Stop()
+
+	c.poll(ctx, sub)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.poll(ctx, sub)
+		}
+	}
+}
+
+// poll runs sub's retry loop for a single tick and delivers the outcome
+// to sub.handler.
+func (c *Collector) poll(ctx context.Context, sub subscription) {
+	log := c.log.With("cmd", sub.cmd)
+
+	get, known := dispatch[sub.cmd]
+	if !known {
+		err := fmt.Errorf("collector: no dispatcher registered for command %q", sub.cmd)
+		log.Error("unsupported command", "error", err)
+		sub.handler(sub.cmd, nil, err)
+		return
+	}
+
+	start := time.Now()
+	var attempt int
+	for {
+		result, err := get(c.cli)
+		if err == nil {
+			log.Debug("poll succeeded", "attempt", attempt, "duration_ms", time.Since(start).Milliseconds())
+			sub.handler(sub.cmd, result, nil)
+			return
+		}
+
+		sleep := c.backoff(attempt)
+		if time.Since(start)+sleep > c.retryTimeout {
+			log.Error("poll retry budget exhausted", "attempt", attempt, "error", err)
+			sub.handler(sub.cmd, nil, fmt.Errorf("collector: giving up on %q after %d attempt(s): %w", sub.cmd, attempt+1, err))
+			return
+		}
+
+		log.Debug("poll failed, retrying", "attempt", attempt, "error", err, "sleep_ms", sleep.Milliseconds())
+		attempt++
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.stop:
+			return
+		case <-time.After(sleep):
+		}
+	}
+}
+
+// backoff returns a jittered exponential backoff duration for the given
+// retry attempt (0-indexed), capped at maxBackoff.
+func (c *Collector) backoff(attempt int) time.Duration {
+	d := c.baseBackoff * time.Duration(1<<uint(attempt))
+	if d > c.maxBackoff || d <= 0 {
+		d = c.maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d/2 + jitter
+}
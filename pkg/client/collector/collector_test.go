@@ -0,0 +1,204 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/paultyng/go-cisco-nx-api/pkg/client"
+)
+
+// flappingServer returns 500 for the first failAttempts requests,
+// counted across all commands, then serves the show version fixture, to
+// exercise the collector's backoff/retry behavior.
+func flappingServer(t *testing.T, failAttempts int32) *httptest.Server {
+	var attempts int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
+		if n := atomic.AddInt32(&attempts, 1); n <= failAttempts {
+			http.Error(w, "simulated flap", http.StatusInternalServerError)
+			return
+		}
+		fc, err := ioutil.ReadFile("../../../assets/requests/resp.show.version.1.json")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(fc)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestCollectorRetriesThroughFlappingServer(t *testing.T) {
+	server := flappingServer(t, 2)
+	defer server.Close()
+
+	srv := strings.Split(server.URL, ":")
+	port, _ := strconv.Atoi(srv[2])
+
+	cli := client.NewClient()
+	cli.SetHost("127.0.0.1")
+	cli.SetPort(port)
+	cli.SetProtocol(srv[0])
+	cli.SetUsername("admin")
+	cli.SetPassword("cisco")
+
+	c := New(cli, WithRetryTimeout(5*time.Second), WithBackoff(10*time.Millisecond, 50*time.Millisecond))
+
+	var mu sync.Mutex
+	var results []interface{}
+	var errs []error
+	done := make(chan struct{}, 1)
+
+	c.Subscribe("show version", time.Hour, func(cmd string, result interface{}, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+		errs = append(errs, err)
+		select {
+		case done <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("collector: Start: %s", err)
+	}
+	defer c.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("collector: timed out waiting for poll result")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) == 0 || errs[0] != nil {
+		t.Fatalf("collector: expected a successful poll after retries, got err=%v", errs)
+	}
+	info, ok := results[0].(*client.SystemInfo)
+	if !ok {
+		t.Fatalf("collector: expected *client.SystemInfo, got %T", results[0])
+	}
+	if info.Hostname == "" {
+		t.Fatal("collector: expected a non-empty hostname")
+	}
+}
+
+func TestCollectorGivesUpAfterRetryTimeout(t *testing.T) {
+	server := flappingServer(t, 1000)
+	defer server.Close()
+
+	srv := strings.Split(server.URL, ":")
+	port, _ := strconv.Atoi(srv[2])
+
+	cli := client.NewClient()
+	cli.SetHost("127.0.0.1")
+	cli.SetPort(port)
+	cli.SetProtocol(srv[0])
+	cli.SetUsername("admin")
+	cli.SetPassword("cisco")
+
+	c := New(cli, WithRetryTimeout(100*time.Millisecond), WithBackoff(10*time.Millisecond, 20*time.Millisecond))
+
+	done := make(chan error, 1)
+	c.Subscribe("show version", time.Hour, func(cmd string, result interface{}, err error) {
+		done <- err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("collector: Start: %s", err)
+	}
+	defer c.Stop()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("collector: expected an error once the retry budget was exhausted")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("collector: timed out waiting for retry budget to be exhausted")
+	}
+}
+
+// TestCollectorConcurrentSubscriptionsShareClient polls two commands
+// against a single client.Client concurrently, the way Start spawns one
+// goroutine per subscription; run with -race, this catches a shared
+// Client field (e.g. the per-request ID counter) being mutated without
+// synchronization.
+func TestCollectorConcurrentSubscriptionsShareClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
+		fc, err := ioutil.ReadFile("../../../assets/requests/resp.show.version.1.json")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(fc)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	srv := strings.Split(server.URL, ":")
+	port, _ := strconv.Atoi(srv[2])
+
+	cli := client.NewClient()
+	cli.SetHost("127.0.0.1")
+	cli.SetPort(port)
+	cli.SetProtocol(srv[0])
+	cli.SetUsername("admin")
+	cli.SetPassword("cisco")
+
+	c := New(cli, WithRetryTimeout(5*time.Second), WithBackoff(10*time.Millisecond, 50*time.Millisecond))
+
+	var got int32
+	handler := func(cmd string, result interface{}, err error) {
+		if err != nil {
+			return
+		}
+		atomic.AddInt32(&got, 1)
+	}
+	c.Subscribe("show version", time.Millisecond, handler)
+	c.Subscribe("show version", time.Millisecond, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("collector: Start: %s", err)
+	}
+
+	for i := 0; i < 50 && atomic.LoadInt32(&got) < 20; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Stop()
+
+	if atomic.LoadInt32(&got) < 2 {
+		t.Fatalf("collector: expected at least 2 successful polls across both subscriptions, got %d", got)
+	}
+}
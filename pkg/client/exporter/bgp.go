@@ -0,0 +1,64 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// bgpPeer is one parsed row of a "show ip bgp summary vrf all" neighbor
+// table.
+type bgpPeer struct {
+	neighbor         string
+	vrf              string
+	prefixesReceived int
+	established      bool
+}
+
+var (
+	vrfHeaderRe = regexp.MustCompile(`^BGP summary information for VRF (\S+),`)
+	neighborRe  = regexp.MustCompile(`^(\d{1,3}(?:\.\d{1,3}){3})\s+\d+\s+\d+\s+\d+\s+\d+\s+\d+\s+\d+\s+\d+\s+\S+\s+(\S+)$`)
+)
+
+// parseBGPPeers scrapes the ASCII text returned by
+// "show ip bgp summary vrf all" for neighbor rows. NX-API only returns
+// this command as free-form text (see client.BgpSummary), so this is a
+// best-effort line parser rather than a structured JSON decode: a peer
+// is considered established when its last column is a numeric prefix
+// count rather than a state string like "Idle" or "Active".
+func parseBGPPeers(text string) []bgpPeer {
+	var peers []bgpPeer
+	vrf := "default"
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if m := vrfHeaderRe.FindStringSubmatch(line); m != nil {
+			vrf = m[1]
+			continue
+		}
+		m := neighborRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		peer := bgpPeer{neighbor: m[1], vrf: vrf}
+		if n, err := strconv.Atoi(m[2]); err == nil {
+			peer.prefixesReceived = n
+			peer.established = true
+		}
+		peers = append(peers, peer)
+	}
+	return peers
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CachingExporter wraps an Exporter and refreshes its metrics in the
+// background on a fixed interval instead of on every Prometheus scrape.
+// It exists for devices where a full collection is too slow or too
+// expensive to run synchronously on each scrape.
+type CachingExporter struct {
+	inner *Exporter
+
+	mu     sync.RWMutex
+	cached []prometheus.Metric
+}
+
+// NewCachingExporter returns a CachingExporter that polls exp every
+// interval. Configure exp (including its Enabled flags) before passing
+// it in. The first collection happens synchronously so the cache is
+// warm before NewCachingExporter returns.
+func NewCachingExporter(exp *Exporter, interval time.Duration) *CachingExporter {
+	ce := &CachingExporter{inner: exp}
+	ce.refresh()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ce.refresh()
+		}
+	}()
+	return ce
+}
+
+// Enable returns a pointer to the underlying Exporter's Enabled flags.
+func (ce *CachingExporter) Enable() *Enabled {
+	return ce.inner.Enable()
+}
+
+func (ce *CachingExporter) refresh() {
+	ch := make(chan prometheus.Metric, 128)
+	go func() {
+		ce.inner.Collect(ch)
+		close(ch)
+	}()
+
+	var metrics []prometheus.Metric
+	for m := range ch {
+		metrics = append(metrics, m)
+	}
+
+	ce.mu.Lock()
+	ce.cached = metrics
+	ce.mu.Unlock()
+}
+
+// Describe implements prometheus.Collector.
+func (ce *CachingExporter) Describe(ch chan<- *prometheus.Desc) {
+	ce.inner.Describe(ch)
+}
+
+// Collect implements prometheus.Collector by serving the most recently
+// cached metrics rather than querying the device.
+func (ce *CachingExporter) Collect(ch chan<- prometheus.Metric) {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+	for _, m := range ce.cached {
+		ch <- m
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exporter turns the results of client.Client's Get* methods
+// into Prometheus metrics, so a Cisco NX-OS device can be scraped like
+// any other Prometheus target.
+package exporter
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/paultyng/go-cisco-nx-api/pkg/client"
+)
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
+
+const namespace = "nxapi"
+
+var (
+	cpuUtilDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cpu", "utilization_percent"),
+		"Per-CPU utilization percentage, from show system resources.",
+		[]string{"cpu", "mode"}, nil,
+	)
+	loadAvgDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "system", "load_average"),
+		"System load average, from show system resources.",
+		[]string{"period"}, nil,
+	)
+	memoryTotalDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "system", "memory_usage_total_kb"),
+		"Total memory usage in KB, from show system resources.",
+		nil, nil,
+	)
+
+	fanStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "environment", "fan_ok"),
+		"1 if the fan reports an Ok status, 0 otherwise, from show environment.",
+		[]string{"fan", "model"}, nil,
+	)
+	fanRPMDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "environment", "fan_rpm"),
+		"Fan speed in RPM, from show environment.",
+		[]string{"fan", "model"}, nil,
+	)
+	psuWattsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "environment", "psu_input_watts"),
+		"Power supply input wattage, from show environment.",
+		[]string{"psu", "model"}, nil,
+	)
+	sensorTempDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "environment", "sensor_celsius"),
+		"Sensor temperature reading in Celsius, from show environment.",
+		[]string{"sensor"}, nil,
+	)
+
+	ifaceStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "interface", "up"),
+		"1 if the interface state is \"up\", 0 otherwise, from show interface.",
+		[]string{"interface"}, nil,
+	)
+	ifaceInRateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "interface", "input_bits_per_second"),
+		"Interface input rate in bits per second, from show interface.",
+		[]string{"interface"}, nil,
+	)
+	ifaceOutRateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "interface", "output_bits_per_second"),
+		"Interface output rate in bits per second, from show interface.",
+		[]string{"interface"}, nil,
+	)
+
+	transceiverTxPowerDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "transceiver", "tx_power_dbm"),
+		"Transceiver transmit optical power in dBm, from show interface transceiver details.",
+		[]string{"interface"}, nil,
+	)
+	transceiverRxPowerDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "transceiver", "rx_power_dbm"),
+		"Transceiver receive optical power in dBm, from show interface transceiver details.",
+		[]string{"interface"}, nil,
+	)
+
+	bgpPeerStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp", "peer_up"),
+		"1 if the BGP peer state reports an established session (a numeric prefix count), 0 otherwise, from show ip bgp summary vrf all.",
+		[]string{"neighbor", "vrf"}, nil,
+	)
+	bgpPrefixesReceivedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp", "prefixes_received"),
+		"Prefixes received from a BGP peer, from show ip bgp summary vrf all.",
+		[]string{"neighbor", "vrf"}, nil,
+	)
+)
+
+// Enabled controls which show commands Exporter scrapes on each Collect.
+// All fields default to true via NewExporter.
+type Enabled struct {
+	SystemResources   bool
+	SystemEnvironment bool
+	Interfaces        bool
+	Transceivers      bool
+	BgpSummary        bool
+}
+
+// Exporter implements prometheus.Collector by fetching from a
+// client.Client on every scrape.
+type Exporter struct {
+	cli     *client.Client
+	enabled Enabled
+}
+
+// NewExporter returns an Exporter that scrapes every supported command.
+// Use Exporter.Enable to disable individual commands.
+func NewExporter(cli *client.Client) *Exporter {
+	return &Exporter{
+		cli: cli,
+		enabled: Enabled{
+			SystemResources:   true,
+			SystemEnvironment: true,
+			Interfaces:        true,
+			Transceivers:      true,
+			BgpSummary:        true,
+		},
+	}
+}
+
+// Enable returns a pointer to the Exporter's Enabled flags so callers can
+// toggle individual commands before registering it.
+func (e *Exporter) Enable() *Enabled {
+	return &e.enabled
+}
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cpuUtilDesc
+	ch <- loadAvgDesc
+	ch <- memoryTotalDesc
+	ch <- fanStateDesc
+	ch <- fanRPMDesc
+	ch <- psuWattsDesc
+	ch <- sensorTempDesc
+	ch <- ifaceStateDesc
+	ch <- ifaceInRateDesc
+	ch <- ifaceOutRateDesc
+	ch <- transceiverTxPowerDesc
+	ch <- transceiverRxPowerDesc
+	ch <- bgpPeerStateDesc
+	ch <- bgpPrefixesReceivedDesc
+}
+
+// Collect implements prometheus.Collector. Each enabled command is
+// fetched independently; a failure on one command does not prevent the
+// others from reporting.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	if e.enabled.SystemResources {
+		e.collectSystemResources(ch)
+	}
+	if e.enabled.SystemEnvironment {
+		e.collectSystemEnvironment(ch)
+	}
+	if e.enabled.Interfaces {
+		e.collectInterfaces(ch)
+	}
+	if e.enabled.Transceivers {
+		e.collectTransceivers(ch)
+	}
+	if e.enabled.BgpSummary {
+		e.collectBgpSummary(ch)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (e *Exporter) collectSystemResources(ch chan<- prometheus.Metric) {
+	resources, err := e.cli.GetSystemResources()
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(loadAvgDesc, prometheus.GaugeValue, resources.Load1Min, "1min")
+	ch <- prometheus.MustNewConstMetric(loadAvgDesc, prometheus.GaugeValue, resources.Load5Min, "5min")
+	ch <- prometheus.MustNewConstMetric(loadAvgDesc, prometheus.GaugeValue, resources.Load15Min, "15min")
+	ch <- prometheus.MustNewConstMetric(memoryTotalDesc, prometheus.GaugeValue, float64(resources.MemoryKB))
+	for _, cpu := range resources.CPUs {
+		id := itoa(cpu.ID)
+		ch <- prometheus.MustNewConstMetric(cpuUtilDesc, prometheus.GaugeValue, cpu.User, id, "user")
+		ch <- prometheus.MustNewConstMetric(cpuUtilDesc, prometheus.GaugeValue, cpu.Kernel, id, "kernel")
+		ch <- prometheus.MustNewConstMetric(cpuUtilDesc, prometheus.GaugeValue, cpu.Idle, id, "idle")
+	}
+}
+
+func (e *Exporter) collectSystemEnvironment(ch chan<- prometheus.Metric) {
+	env, err := e.cli.GetSystemEnvironment()
+	if err != nil {
+		return
+	}
+	for _, fan := range env.Fans {
+		ch <- prometheus.MustNewConstMetric(fanStateDesc, prometheus.GaugeValue, boolToFloat(fan.State == "Ok"), fan.Name, fan.Model)
+		ch <- prometheus.MustNewConstMetric(fanRPMDesc, prometheus.GaugeValue, float64(fan.RPM), fan.Name, fan.Model)
+	}
+	for _, psu := range env.PowerSupplies {
+		ch <- prometheus.MustNewConstMetric(psuWattsDesc, prometheus.GaugeValue, psu.WattsInput, itoa(psu.Number), psu.Model)
+	}
+	for _, sensor := range env.Sensors {
+		ch <- prometheus.MustNewConstMetric(sensorTempDesc, prometheus.GaugeValue, sensor.Reading, sensor.Name)
+	}
+}
+
+func (e *Exporter) collectInterfaces(ch chan<- prometheus.Metric) {
+	ifaces, err := e.cli.GetInterfaces()
+	if err != nil {
+		return
+	}
+	for _, iface := range ifaces {
+		ch <- prometheus.MustNewConstMetric(ifaceStateDesc, prometheus.GaugeValue, boolToFloat(iface.State == "up"), iface.Name)
+		ch <- prometheus.MustNewConstMetric(ifaceInRateDesc, prometheus.GaugeValue, float64(iface.InputRate), iface.Name)
+		ch <- prometheus.MustNewConstMetric(ifaceOutRateDesc, prometheus.GaugeValue, float64(iface.OutputRate), iface.Name)
+	}
+}
+
+func (e *Exporter) collectTransceivers(ch chan<- prometheus.Metric) {
+	transceivers, err := e.cli.GetTransceivers()
+	if err != nil {
+		return
+	}
+	for _, t := range transceivers {
+		ch <- prometheus.MustNewConstMetric(transceiverTxPowerDesc, prometheus.GaugeValue, t.TxPowerDBm, t.Interface)
+		ch <- prometheus.MustNewConstMetric(transceiverRxPowerDesc, prometheus.GaugeValue, t.RxPowerDBm, t.Interface)
+	}
+}
+
+func (e *Exporter) collectBgpSummary(ch chan<- prometheus.Metric) {
+	summary, err := e.cli.GetBgpSummary()
+	if err != nil {
+		return
+	}
+	for _, peer := range parseBGPPeers(summary.Text) {
+		ch <- prometheus.MustNewConstMetric(bgpPeerStateDesc, prometheus.GaugeValue, boolToFloat(peer.established), peer.neighbor, peer.vrf)
+		ch <- prometheus.MustNewConstMetric(bgpPrefixesReceivedDesc, prometheus.GaugeValue, float64(peer.prefixesReceived), peer.neighbor, peer.vrf)
+	}
+}
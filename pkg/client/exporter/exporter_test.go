@@ -0,0 +1,198 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/paultyng/go-cisco-nx-api/pkg/client"
+)
+
+// newTestServer mirrors the mux used by client.TestClient: it dispatches
+// the command embedded in the ins_api request body to a fixture file
+// under assets/requests.
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
+		dataDir := "../../../assets/requests"
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !bytes.Contains(body, []byte(`"ins_api":`)) {
+			http.Error(w, fmt.Sprintf("unsupported payload: %s", body), http.StatusBadRequest)
+			return
+		}
+		var j *client.InsAPIRequest
+		if err := json.Unmarshal(body, &j); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var fp string
+		switch j.Params.Input {
+		case "show system resources":
+			fp = dataDir + "/resp.show.system.resources.1.json"
+		case "show environment":
+			fp = dataDir + "/resp.show.environment.1.json"
+		case "show interface":
+			fp = dataDir + "/resp.show.interfaces.4.json"
+		case "show interface transceiver details":
+			fp = dataDir + "/resp.show.interface.transceiver.details.1.json"
+		case "show ip bgp summary vrf all":
+			fp = dataDir + "/resp.show.ip.bgp.summary.vrf.all.1.json"
+		default:
+			http.Error(w, fmt.Sprintf("unsupported command: %s", j.Params.Input), http.StatusBadRequest)
+			return
+		}
+		fc, err := ioutil.ReadFile(fp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(fc)
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *client.Client {
+	srv := strings.Split(server.URL, ":")
+	port, _ := strconv.Atoi(srv[2])
+
+	cli := client.NewClient()
+	cli.SetHost("127.0.0.1")
+	cli.SetPort(port)
+	cli.SetProtocol(srv[0])
+	cli.SetUsername("admin")
+	cli.SetPassword("cisco")
+	return cli
+}
+
+// gather runs the full Describe/Collect/Gather cycle through a
+// prometheus.Registry, the same path promhttp.Handler exercises.
+func gather(t *testing.T, exp *Exporter) []*dto.MetricFamily {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(exp); err != nil {
+		t.Fatalf("exporter: Register: %s", err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("exporter: Gather: %s", err)
+	}
+	return families
+}
+
+func findFamily(families []*dto.MetricFamily, name string) *dto.MetricFamily {
+	for _, f := range families {
+		if f.GetName() == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func findLabel(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+func TestExporterMetrics(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	cli := newTestClient(t, server)
+	exp := NewExporter(cli)
+	families := gather(t, exp)
+
+	tests := []struct {
+		name        string
+		metricName  string
+		wantCount   int
+		wantLabel   string
+		wantLabelOn string
+	}{
+		{name: "cpu utilization", metricName: "nxapi_cpu_utilization_percent", wantCount: 6, wantLabel: "cpu", wantLabelOn: "0"},
+		{name: "load average", metricName: "nxapi_system_load_average", wantCount: 3, wantLabel: "period", wantLabelOn: "1min"},
+		{name: "fan state", metricName: "nxapi_environment_fan_ok", wantCount: 2, wantLabel: "fan", wantLabelOn: "Fan1(sys_fan1)"},
+		{name: "fan rpm", metricName: "nxapi_environment_fan_rpm", wantCount: 2, wantLabel: "fan", wantLabelOn: "Fan1(sys_fan1)"},
+		{name: "psu watts", metricName: "nxapi_environment_psu_input_watts", wantCount: 2, wantLabel: "psu", wantLabelOn: "1"},
+		{name: "sensor temp", metricName: "nxapi_environment_sensor_celsius", wantCount: 2, wantLabel: "sensor", wantLabelOn: "Inlet"},
+		{name: "interface up", metricName: "nxapi_interface_up", wantCount: 4, wantLabel: "interface", wantLabelOn: "mgmt0"},
+		{name: "transceiver tx power", metricName: "nxapi_transceiver_tx_power_dbm", wantCount: 2, wantLabel: "interface", wantLabelOn: "Ethernet1/1"},
+		{name: "bgp prefixes received", metricName: "nxapi_bgp_prefixes_received", wantCount: 1, wantLabel: "neighbor", wantLabelOn: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			family := findFamily(families, tt.metricName)
+			if family == nil {
+				t.Fatalf("exporter: missing metric family %q", tt.metricName)
+			}
+			if got := len(family.GetMetric()); got != tt.wantCount {
+				t.Fatalf("exporter: %q: got %d metrics, want %d", tt.metricName, got, tt.wantCount)
+			}
+			found := false
+			for _, m := range family.GetMetric() {
+				if findLabel(m, tt.wantLabel) == tt.wantLabelOn {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("exporter: %q: expected a metric with %s=%q", tt.metricName, tt.wantLabel, tt.wantLabelOn)
+			}
+		})
+	}
+
+	fanRPM := findFamily(families, "nxapi_environment_fan_rpm")
+	var gotRPM float64
+	for _, m := range fanRPM.GetMetric() {
+		if findLabel(m, "fan") == "Fan1(sys_fan1)" {
+			gotRPM = m.GetGauge().GetValue()
+		}
+	}
+	if gotRPM != 6600 {
+		t.Fatalf("exporter: nxapi_environment_fan_rpm: got %v for Fan1(sys_fan1), want 6600", gotRPM)
+	}
+}
+
+func TestExporterRespectsEnabledFlags(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	cli := newTestClient(t, server)
+	exp := NewExporter(cli)
+	exp.Enable().BgpSummary = false
+
+	families := gather(t, exp)
+	if family := findFamily(families, "nxapi_bgp_prefixes_received"); family != nil {
+		t.Fatalf("exporter: expected nxapi_bgp_prefixes_received to be absent when BgpSummary is disabled")
+	}
+}
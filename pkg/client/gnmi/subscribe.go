@@ -0,0 +1,124 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+	"encoding/json"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/paultyng/go-cisco-nx-api/pkg/client"
+)
+
+// InterfaceEvent is a single change delivered by SubscribeInterfaces.
+// Deleted is set when the update is a gNMI delete rather than a value
+// change, in which case Interface only has its Name populated.
+type InterfaceEvent struct {
+	Interface client.Interface
+	Deleted   bool
+	Err       error
+}
+
+// SubscribeInterfaces opens a gNMI ON_CHANGE subscription against the
+// "show interface" path mapping and streams one InterfaceEvent per
+// update or delete. The returned channel is closed when ctx is
+// cancelled or the subscription stream ends; a terminal stream error is
+// delivered as a final InterfaceEvent with Err set before the channel
+// closes.
+func (t *Transport) SubscribeInterfaces(ctx context.Context) <-chan InterfaceEvent {
+	events := make(chan InterfaceEvent)
+
+	go func() {
+		defer close(events)
+
+		stream, err := t.client.Subscribe(ctx)
+		if err != nil {
+			events <- InterfaceEvent{Err: err}
+			return
+		}
+
+		sub := &gnmipb.SubscribeRequest{
+			Request: &gnmipb.SubscribeRequest_Subscribe{
+				Subscribe: &gnmipb.SubscriptionList{
+					Prefix: gnmiPath(t.paths["show interface"]),
+					Mode:   gnmipb.SubscriptionList_STREAM,
+					Subscription: []*gnmipb.Subscription{
+						{Mode: gnmipb.SubscriptionMode_ON_CHANGE},
+					},
+				},
+			},
+		}
+		if err := stream.Send(sub); err != nil {
+			events <- InterfaceEvent{Err: err}
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				select {
+				case <-ctx.Done():
+				default:
+					events <- InterfaceEvent{Err: err}
+				}
+				return
+			}
+
+			update, ok := resp.GetResponse().(*gnmipb.SubscribeResponse_Update)
+			if !ok {
+				continue
+			}
+
+			for _, del := range update.Update.GetDelete() {
+				select {
+				case events <- InterfaceEvent{Deleted: true, Interface: client.Interface{Name: pathKey(del, "name")}}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			for _, u := range update.Update.GetUpdate() {
+				iface := client.Interface{Name: pathKey(u.GetPath(), "name")}
+				raw := u.GetVal().GetJsonIetfVal()
+				if raw == nil {
+					raw = u.GetVal().GetJsonVal()
+				}
+				if raw != nil {
+					if err := json.Unmarshal(raw, &iface); err != nil {
+						events <- InterfaceEvent{Err: err}
+						continue
+					}
+				}
+				select {
+				case events <- InterfaceEvent{Interface: iface}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events
+}
+
+// pathKey returns the value of key on the last path element that
+// defines it, or "" if none do.
+func pathKey(path *gnmipb.Path, key string) string {
+	for i := len(path.GetElem()) - 1; i >= 0; i-- {
+		if v, ok := path.GetElem()[i].GetKey()[key]; ok {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,214 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gnmi implements client.Transport over gNMI Get/Subscribe RPCs,
+// as an alternative to the client package's default NX-API HTTP/JSON
+// transport. It lives in its own package, rather than client itself, so
+// that callers who only want NX-API REST don't pull in gRPC and the
+// gNMI protobufs.
+package gnmi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/paultyng/go-cisco-nx-api/pkg/client"
+	"google.golang.org/grpc"
+)
+
+// nopLogger discards everything. It is the default Logger for a
+// Transport, mirroring client's own nopLogger, so callers never need to
+// nil-check before SetLogger is called.
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, fields ...interface{})    {}
+func (nopLogger) Info(msg string, fields ...interface{})     {}
+func (nopLogger) Error(msg string, fields ...interface{})    {}
+func (l nopLogger) With(fields ...interface{}) client.Logger { return l }
+
+// DialTransport dials target over gRPC and returns a Transport wrapping
+// the resulting client, along with a close func the caller should defer.
+// fallback is passed straight through to NewTransport for commands gNMI
+// doesn't map; pass any additional dial options (TLS credentials,
+// keepalive, etc.) the same way you would to grpc.DialContext directly.
+func DialTransport(ctx context.Context, target string, fallback client.Transport, opts ...grpc.DialOption) (*Transport, func() error, error) {
+	conn, err := grpc.DialContext(ctx, target, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gnmi: failed to dial target %q: %s", target, err)
+	}
+	return NewTransport(gnmipb.NewGNMIClient(conn), target, fallback), conn.Close, nil
+}
+
+// paths maps a show command onto the gNMI path queried in its place.
+// Only commands whose state gNMI models well as structured paths are
+// covered; everything else is routed to Transport's fallback, if one is
+// set, rather than being rejected outright.
+var paths = map[string][]string{
+	"show interface":                     {"interfaces", "interface"},
+	"show ip bgp summary vrf all":        {"network-instances", "network-instance", "protocols", "protocol", "bgp", "neighbors", "neighbor"},
+	"show environment":                   {"components", "component"},
+	"show interface transceiver details": {"components", "component", "transceiver"},
+}
+
+// Transport implements client.Transport over gNMI Get/Subscribe RPCs
+// instead of NX-API HTTP/JSON. It is constructed around an existing gNMI
+// client so callers control dial options (TLS, credentials, keepalive)
+// the same way they would for any other gNMI client.
+//
+// Only the commands named in paths are actually served over gNMI;
+// everything else is handed to fallback, so a Client configured with a
+// Transport can still run show-style commands (e.g. "show version",
+// "show vlan") through NX-API REST instead of hard-erroring on them.
+type Transport struct {
+	client   gnmipb.GNMIClient
+	target   string
+	paths    map[string][]string
+	fallback client.Transport
+	log      client.Logger
+}
+
+// NewTransport returns a Transport that issues RPCs through gnmiClient,
+// addressing the device identified by target (gNMI's Path.Target / the
+// "-t" flag of gnmic-style tools). Most callers should use DialTransport
+// instead; NewTransport takes the client interface directly so tests can
+// supply a fake one.
+//
+// Commands with no gNMI path mapping are routed to fallback, typically
+// the same Client's RESTTransport(), so hybrid gNMI/NX-API deployments
+// only need to configure one Transport. fallback may be nil, in which
+// case unmapped commands fail with an error instead.
+func NewTransport(gnmiClient gnmipb.GNMIClient, target string, fallback client.Transport) *Transport {
+	return &Transport{
+		client:   gnmiClient,
+		target:   target,
+		paths:    paths,
+		fallback: fallback,
+		log:      nopLogger{},
+	}
+}
+
+// SetLogger wires a client.Logger into the transport for pre/post
+// request logging, mirroring Client.SetLogger.
+func (t *Transport) SetLogger(logger client.Logger) {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	t.log = logger
+}
+
+func gnmiPath(elems []string) *gnmipb.Path {
+	path := &gnmipb.Path{}
+	for _, e := range elems {
+		path.Elem = append(path.Elem, &gnmipb.PathElem{Name: e})
+	}
+	return path
+}
+
+// Execute implements client.Transport. Commands with a gNMI path
+// mapping are issued as a gNMI Get and the result is handed to
+// notificationsToBody; a JSON-encoded value is returned unchanged, so
+// the existing Get* parsers decode it only when the target encodes it
+// in the same body shape the REST transport's ins_api envelope carries
+// for that command. No per-command reshaping is done. Everything else
+// is delegated to fallback.
+func (t *Transport) Execute(cmd string) (json.RawMessage, error) {
+	elems, ok := t.paths[cmd]
+	if !ok {
+		if t.fallback != nil {
+			return t.fallback.Execute(cmd)
+		}
+		return nil, fmt.Errorf("gnmi: no path mapping for command %q", cmd)
+	}
+
+	log := t.log.With("target", t.target, "cmd", cmd)
+
+	req := &gnmipb.GetRequest{
+		Path:     []*gnmipb.Path{gnmiPath(elems)},
+		Encoding: gnmipb.Encoding_JSON_IETF,
+	}
+	resp, err := t.client.Get(context.Background(), req)
+	if err != nil {
+		log.Error("gnmi Get failed", "error", err)
+		return nil, fmt.Errorf("gnmi: Get for %q failed: %s", cmd, err)
+	}
+
+	body, err := notificationsToBody(cmd, resp.GetNotification())
+	if err != nil {
+		log.Error("failed to reshape gnmi notifications", "error", err)
+		return nil, fmt.Errorf("gnmi: failed to reshape response for %q: %s", cmd, err)
+	}
+	log.Info("gnmi Get completed")
+	return body, nil
+}
+
+// notificationsToBody extracts the value(s) carried in a set of gNMI
+// notifications. NX-OS gNMI targets typically encode whole subtrees as a
+// single JSON_IETF-valued update rather than one update per leaf, so the
+// common case is a single update whose JSON value is returned
+// unmodified; the first JSON-valued update found is returned as is and
+// every other update is ignored. When no update carries JSON, the
+// scalar values are merged into a {"rows": [...]} wrapper keyed off the
+// leaf path keys. That wrapper is not the body shape any Get* parser
+// reads, so per-leaf encodings do not decode into typed values.
+func notificationsToBody(cmd string, notifications []*gnmipb.Notification) (json.RawMessage, error) {
+	var rows []map[string]interface{}
+	for _, n := range notifications {
+		for _, u := range n.GetUpdate() {
+			val := u.GetVal()
+			if raw := val.GetJsonIetfVal(); raw != nil {
+				return json.RawMessage(raw), nil
+			}
+			if raw := val.GetJsonVal(); raw != nil {
+				return json.RawMessage(raw), nil
+			}
+			row := map[string]interface{}{}
+			for _, elem := range u.GetPath().GetElem() {
+				for k, v := range elem.GetKey() {
+					row[k] = v
+				}
+			}
+			row["value"] = scalarValue(val)
+			rows = append(rows, row)
+		}
+	}
+	if rows == nil {
+		return nil, fmt.Errorf("gnmi: no updates for command %q", cmd)
+	}
+	return json.Marshal(map[string]interface{}{"rows": rows})
+}
+
+// scalarValue extracts whichever scalar is set on a gNMI TypedValue, for
+// the leaf-by-leaf encoding fallback in notificationsToBody. It switches
+// on the TypedValue's oneof member rather than comparing against each
+// field's zero value, so a legitimately zero-valued leaf (empty string,
+// 0, false) is reported correctly instead of being mistaken for "unset"
+// and falling through to the next case.
+func scalarValue(val *gnmipb.TypedValue) interface{} {
+	switch v := val.GetValue().(type) {
+	case *gnmipb.TypedValue_StringVal:
+		return v.StringVal
+	case *gnmipb.TypedValue_IntVal:
+		return v.IntVal
+	case *gnmipb.TypedValue_UintVal:
+		return v.UintVal
+	case *gnmipb.TypedValue_FloatVal:
+		return v.FloatVal
+	case *gnmipb.TypedValue_BoolVal:
+		return v.BoolVal
+	default:
+		return nil
+	}
+}
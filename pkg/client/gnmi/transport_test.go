@@ -0,0 +1,248 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/paultyng/go-cisco-nx-api/pkg/client"
+	"google.golang.org/grpc"
+)
+
+// fakeGNMIClient implements gnmipb.GNMIClient in-process, standing in
+// for a real gNMI server so Transport can be exercised without a live
+// device.
+type fakeGNMIClient struct {
+	getResponse *gnmipb.GetResponse
+	getErr      error
+	subUpdates  []*gnmipb.SubscribeResponse
+}
+
+func (f *fakeGNMIClient) Capabilities(ctx context.Context, in *gnmipb.CapabilityRequest, opts ...grpc.CallOption) (*gnmipb.CapabilityResponse, error) {
+	return &gnmipb.CapabilityResponse{}, nil
+}
+
+func (f *fakeGNMIClient) Get(ctx context.Context, in *gnmipb.GetRequest, opts ...grpc.CallOption) (*gnmipb.GetResponse, error) {
+	return f.getResponse, f.getErr
+}
+
+func (f *fakeGNMIClient) Set(ctx context.Context, in *gnmipb.SetRequest, opts ...grpc.CallOption) (*gnmipb.SetResponse, error) {
+	return &gnmipb.SetResponse{}, nil
+}
+
+func (f *fakeGNMIClient) Subscribe(ctx context.Context, opts ...grpc.CallOption) (gnmipb.GNMI_SubscribeClient, error) {
+	return &fakeSubscribeClient{ctx: ctx, updates: f.subUpdates}, nil
+}
+
+// fakeSubscribeClient implements gnmipb.GNMI_SubscribeClient, which
+// embeds grpc.ClientStream; ClientStream is embedded here as a nil
+// interface purely to satisfy that embedding, since nothing under test
+// calls its methods.
+type fakeSubscribeClient struct {
+	grpc.ClientStream
+
+	ctx     context.Context
+	updates []*gnmipb.SubscribeResponse
+	sent    bool
+	next    int
+}
+
+func (s *fakeSubscribeClient) Send(*gnmipb.SubscribeRequest) error {
+	s.sent = true
+	return nil
+}
+
+func (s *fakeSubscribeClient) Recv() (*gnmipb.SubscribeResponse, error) {
+	if s.next >= len(s.updates) {
+		<-s.ctx.Done()
+		return nil, s.ctx.Err()
+	}
+	resp := s.updates[s.next]
+	s.next++
+	return resp, nil
+}
+
+func notificationWithJSON(t *testing.T, name string, v interface{}) *gnmipb.Notification {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("gnmi test: marshal: %s", err)
+	}
+	return &gnmipb.Notification{
+		Update: []*gnmipb.Update{
+			{
+				Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: name}}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: raw}},
+			},
+		},
+	}
+}
+
+// TestTransportMatchesRESTTransport checks that, given equivalent
+// fixture data, the gNMI and NX-API REST transports decode "show
+// interface" into the same typed []client.Interface value through
+// Client.GetInterfaces.
+func TestTransportMatchesRESTTransport(t *testing.T) {
+	restIfaces := func() []client.Interface {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
+			fc, err := ioutil.ReadFile("../../../assets/requests/resp.show.interfaces.4.json")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Write(fc)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		srv := strings.Split(server.URL, ":")
+		port, _ := strconv.Atoi(srv[2])
+
+		cli := client.NewClient()
+		cli.SetHost("127.0.0.1")
+		cli.SetPort(port)
+		cli.SetProtocol(srv[0])
+		cli.SetUsername("admin")
+		cli.SetPassword("cisco")
+
+		ifaces, err := cli.GetInterfaces()
+		if err != nil {
+			t.Fatalf("client: REST GetInterfaces: %s", err)
+		}
+		return ifaces
+	}()
+
+	if len(restIfaces) == 0 {
+		t.Fatal("client: expected the REST fixture to contain at least one interface")
+	}
+
+	body := map[string]interface{}{
+		"TABLE_interface": []interface{}{
+			map[string]interface{}{"ROW_interface": restIfaces},
+		},
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("gnmi test: marshal: %s", err)
+	}
+
+	fake := &fakeGNMIClient{
+		getResponse: &gnmipb.GetResponse{
+			Notification: []*gnmipb.Notification{
+				{
+					Update: []*gnmipb.Update{
+						{
+							Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "interfaces"}, {Name: "interface"}}},
+							Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: raw}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	transport := NewTransport(fake, "switch01", nil)
+	cli := client.NewClient(client.WithTransport(transport))
+
+	gnmiIfaces, err := cli.GetInterfaces()
+	if err != nil {
+		t.Fatalf("client: gnmi GetInterfaces: %s", err)
+	}
+
+	if len(gnmiIfaces) != len(restIfaces) {
+		t.Fatalf("client: gnmi transport returned %d interface(s), want %d", len(gnmiIfaces), len(restIfaces))
+	}
+	for i := range restIfaces {
+		if gnmiIfaces[i] != restIfaces[i] {
+			t.Fatalf("client: gnmi transport interface %d = %+v, want %+v", i, gnmiIfaces[i], restIfaces[i])
+		}
+	}
+}
+
+// TestTransportFallsBackToREST checks that a command with no gNMI path
+// mapping (e.g. "show version") is routed to the fallback Transport
+// instead of failing outright.
+func TestTransportFallsBackToREST(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ins", func(w http.ResponseWriter, req *http.Request) {
+		fc, err := ioutil.ReadFile("../../../assets/requests/resp.show.version.1.json")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(fc)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	srv := strings.Split(server.URL, ":")
+	port, _ := strconv.Atoi(srv[2])
+
+	rest := client.NewClient()
+	rest.SetHost("127.0.0.1")
+	rest.SetPort(port)
+	rest.SetProtocol(srv[0])
+	rest.SetUsername("admin")
+	rest.SetPassword("cisco")
+
+	fake := &fakeGNMIClient{getErr: fmt.Errorf("gnmi test: Get should not be called for an unmapped command")}
+	transport := NewTransport(fake, "switch01", rest.RESTTransport())
+	cli := client.NewClient(client.WithTransport(transport))
+
+	info, err := cli.GetSystemInfo()
+	if err != nil {
+		t.Fatalf("client: GetSystemInfo through gnmi Transport with REST fallback: %s", err)
+	}
+	if info.Hostname == "" {
+		t.Fatal("client: expected a non-empty hostname from the REST fallback")
+	}
+}
+
+func TestTransportSubscribeInterfaces(t *testing.T) {
+	update := notificationWithJSON(t, "name", client.Interface{Name: "Ethernet1/1", State: "up"})
+
+	fake := &fakeGNMIClient{
+		subUpdates: []*gnmipb.SubscribeResponse{
+			{Response: &gnmipb.SubscribeResponse_Update{Update: update}},
+		},
+	}
+
+	transport := NewTransport(fake, "switch01", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := transport.SubscribeInterfaces(ctx)
+
+	event, ok := <-events
+	if !ok {
+		t.Fatal("client: expected at least one InterfaceEvent")
+	}
+	if event.Err != nil {
+		t.Fatalf("client: unexpected InterfaceEvent error: %s", event.Err)
+	}
+	if event.Interface.Name != "Ethernet1/1" || event.Interface.State != "up" {
+		t.Fatalf("client: unexpected InterfaceEvent: %+v", event)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// Interface is the parsed result of a single entry in "show interface".
+type Interface struct {
+	Name        string `json:"interface"`
+	State       string `json:"state"`
+	Description string `json:"desc"`
+	InputRate   uint64 `json:"eth_inrate1_bits"`
+	OutputRate  uint64 `json:"eth_outrate1_bits"`
+}
+
+type interfaceBody struct {
+	TableInterface []struct {
+		RowInterface []Interface `json:"ROW_interface"`
+	} `json:"TABLE_interface"`
+}
+
+// GetInterfaces issues "show interface" and returns the parsed interface
+// table.
+func (cli *Client) GetInterfaces() ([]Interface, error) {
+	body := &interfaceBody{}
+	if err := cli.run("show interface", body); err != nil {
+		return nil, err
+	}
+	var ifaces []Interface
+	for _, table := range body.TableInterface {
+		ifaces = append(ifaces, table.RowInterface...)
+	}
+	return ifaces, nil
+}
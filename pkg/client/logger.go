@@ -0,0 +1,43 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// Logger is the structured logging interface used throughout the client
+// package. Every REST/JSON-RPC call logs through it instead of reaching
+// for fmt.Errorf/t.Logf directly, so that callers can swap in whatever
+// logging stack their application already uses. fields are passed as
+// alternating key/value pairs, mirroring zap's SugaredLogger.
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+
+	// With returns a Logger that prepends fields to every subsequent log
+	// call, so callers can inject correlation IDs (e.g. a batch ID when
+	// issuing multiple show commands) without threading them through
+	// every call site.
+	With(fields ...interface{}) Logger
+}
+
+// nopLogger discards everything. It is the default Logger for a Client
+// created via NewClient, so callers never need to nil-check cli.log.
+type nopLogger struct{}
+
+func newNopLogger() Logger { return nopLogger{} }
+
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (l nopLogger) With(fields ...interface{}) Logger     { return l }
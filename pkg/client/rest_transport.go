@@ -0,0 +1,98 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// restTransport is the default Transport: it speaks the classic NX-API
+// "ins_api" HTTP/JSON endpoint, using its owning Client's connection
+// settings (host, port, credentials, HTTP client) so that SetHost et al.
+// keep working exactly as before Transport existed.
+type restTransport struct {
+	cli *Client
+}
+
+// Execute implements Transport by posting cmd to /ins using the ins_api
+// envelope and returning the body of the response.
+func (t *restTransport) Execute(cmd string) (json.RawMessage, error) {
+	cli := t.cli
+	requestID := atomic.AddInt64(&cli.requestID, 1)
+	reqBody := &InsAPIRequest{
+		Params: InsAPIParams{
+			Version:      "1.0",
+			Type:         "cli_show",
+			Chunk:        "0",
+			Sid:          fmt.Sprintf("%d", requestID),
+			Input:        cmd,
+			OutputFormat: "json",
+		},
+	}
+
+	start := time.Now()
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to marshal request for %q: %s", cmd, err)
+	}
+
+	log := cli.log.With("host", cli.host, "cmd", cmd, "payload_bytes", len(payload))
+
+	httpReq, err := http.NewRequest("POST", cli.url(), bytes.NewReader(payload))
+	if err != nil {
+		log.Error("failed to build request", "error", err)
+		return nil, fmt.Errorf("client: failed to build request for %q: %s", cmd, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.SetBasicAuth(cli.username, cli.password)
+
+	resp, err := cli.httpClient.Do(httpReq)
+	if err != nil {
+		log.Error("request failed", "error", err, "duration_ms", time.Since(start).Milliseconds())
+		return nil, fmt.Errorf("client: request for %q failed: %s", cmd, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("failed to read response body", "error", err, "http_status", resp.StatusCode)
+		return nil, fmt.Errorf("client: failed to read response for %q: %s", cmd, err)
+	}
+
+	duration := time.Since(start)
+	if resp.StatusCode != http.StatusOK {
+		log.Error("non-200 response", "http_status", resp.StatusCode, "duration_ms", duration.Milliseconds())
+		return nil, fmt.Errorf("client: request for %q returned HTTP %d: %s", cmd, resp.StatusCode, string(respBody))
+	}
+
+	var envelope InsAPIResponse
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		log.Error("failed to unmarshal envelope", "error", err, "duration_ms", duration.Milliseconds())
+		return nil, fmt.Errorf("client: failed to unmarshal response for %q: %s", cmd, err)
+	}
+	if envelope.InsAPI.Outputs.Output.Code != "" && envelope.InsAPI.Outputs.Output.Code != "200" {
+		log.Error("device reported error", "error", envelope.InsAPI.Outputs.Output.Msg, "duration_ms", duration.Milliseconds())
+		return nil, fmt.Errorf("client: device returned error for %q: %s", cmd, envelope.InsAPI.Outputs.Output.Msg)
+	}
+
+	log.Info("request completed", "http_status", resp.StatusCode, "duration_ms", duration.Milliseconds())
+	return envelope.InsAPI.Outputs.Output.Body, nil
+}
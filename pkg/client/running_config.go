@@ -0,0 +1,32 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// RunningConfiguration is the parsed result of "show running-config".
+// NX-API returns free-form CLI output as a single text blob rather than a
+// structured table, so Text carries the command output verbatim.
+type RunningConfiguration struct {
+	Text string `json:"-"`
+}
+
+// GetRunningConfiguration issues "show running-config" and returns the
+// raw configuration text.
+func (cli *Client) GetRunningConfiguration() (*RunningConfiguration, error) {
+	text, err := cli.getTextOutput("show running-config")
+	if err != nil {
+		return nil, err
+	}
+	return &RunningConfiguration{Text: text}, nil
+}
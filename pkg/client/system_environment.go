@@ -0,0 +1,72 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// Fan is a single fan-tray entry in "show environment".
+type Fan struct {
+	Name  string `json:"fanname"`
+	Model string `json:"fanmodel"`
+	State string `json:"fanstatus"`
+	RPM   int    `json:"fanspeed"`
+}
+
+// PowerSupply is a single PSU entry in "show environment".
+type PowerSupply struct {
+	Number     int     `json:"psunum"`
+	Model      string  `json:"psumodel"`
+	WattsInput float64 `json:"actual_input"`
+	State      string  `json:"ps_status"`
+}
+
+// Sensor is a single temperature sensor entry in "show environment".
+type Sensor struct {
+	Name        string  `json:"sensor"`
+	Reading     float64 `json:"curtemp"`
+	MajorThresh float64 `json:"majthres"`
+	State       string  `json:"alarmstatus"`
+}
+
+type environmentBody struct {
+	TableFanDetails struct {
+		RowFanDetails []Fan `json:"ROW_fan_detail"`
+	} `json:"TABLE_fandetails"`
+	TablePowerSupply struct {
+		RowPowerSupply []PowerSupply `json:"ROW_powersup"`
+	} `json:"TABLE_powersup"`
+	TableTempInfo struct {
+		RowTempInfo []Sensor `json:"ROW_tempinfo"`
+	} `json:"TABLE_tempinfo"`
+}
+
+// SystemEnvironment is the parsed result of "show environment".
+type SystemEnvironment struct {
+	Fans          []Fan
+	PowerSupplies []PowerSupply
+	Sensors       []Sensor
+}
+
+// GetSystemEnvironment issues "show environment" and returns the parsed
+// result.
+func (cli *Client) GetSystemEnvironment() (*SystemEnvironment, error) {
+	body := &environmentBody{}
+	if err := cli.run("show environment", body); err != nil {
+		return nil, err
+	}
+	return &SystemEnvironment{
+		Fans:          body.TableFanDetails.RowFanDetails,
+		PowerSupplies: body.TablePowerSupply.RowPowerSupply,
+		Sensors:       body.TableTempInfo.RowTempInfo,
+	}, nil
+}
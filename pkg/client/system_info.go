@@ -0,0 +1,34 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// SystemInfo is the parsed result of "show version".
+type SystemInfo struct {
+	Hostname         string `json:"host_name"`
+	ProcessorBoardID string `json:"proc_board_id"`
+	Uptime           int    `json:"kern_uptm_days"`
+	KickstartImage   struct {
+		Version string `json:"kickstart_ver_str"`
+	} `json:"kickstart"`
+}
+
+// GetSystemInfo issues "show version" and returns the parsed result.
+func (cli *Client) GetSystemInfo() (*SystemInfo, error) {
+	info := &SystemInfo{}
+	if err := cli.run("show version", info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
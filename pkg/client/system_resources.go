@@ -0,0 +1,56 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// CPU is the per-core utilization breakdown of a single CPU entry in
+// "show system resources".
+type CPU struct {
+	ID     int     `json:"cpuid"`
+	User   float64 `json:"user"`
+	Kernel float64 `json:"kernel"`
+	Idle   float64 `json:"idle"`
+}
+
+// Processes summarizes the process counts of "show system resources".
+type Processes struct {
+	Total   int `json:"processes_total"`
+	Running int `json:"processes_running"`
+}
+
+type cpuTable struct {
+	RowCPU []CPU `json:"ROW_cpu_util"`
+}
+
+// SystemResources is the parsed result of "show system resources".
+type SystemResources struct {
+	Load1Min  float64   `json:"load_avg_1min"`
+	Load5Min  float64   `json:"load_avg_5min"`
+	Load15Min float64   `json:"load_avg_15min"`
+	MemoryKB  uint64    `json:"memory_usage_total"`
+	Processes Processes `json:"processes"`
+	TableCPU  cpuTable  `json:"TABLE_cpu_util"`
+	CPUs      []CPU     `json:"-"`
+}
+
+// GetSystemResources issues "show system resources" and returns the
+// parsed result.
+func (cli *Client) GetSystemResources() (*SystemResources, error) {
+	resources := &SystemResources{}
+	if err := cli.run("show system resources", resources); err != nil {
+		return nil, err
+	}
+	resources.CPUs = resources.TableCPU.RowCPU
+	return resources, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "encoding/json"
+
+// getTextOutput issues cmd and returns its body as a raw string. A
+// handful of show commands (running-config, bgp summary) are only
+// meaningful as ASCII text rather than a structured table, so callers
+// wrap this helper instead of cli.run.
+func (cli *Client) getTextOutput(cmd string) (string, error) {
+	var raw json.RawMessage
+	if err := cli.run(cmd, &raw); err != nil {
+		return "", err
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		text = string(raw)
+	}
+	return text, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// Transceiver is a single entry in "show interface transceiver details".
+type Transceiver struct {
+	Interface    string  `json:"interface"`
+	Type         string  `json:"sfp"`
+	SerialNo     string  `json:"serialnum"`
+	TxPowerDBm   float64 `json:"txpower"`
+	RxPowerDBm   float64 `json:"rxpower"`
+	TemperatureC float64 `json:"temperature"`
+}
+
+type transceiverBody struct {
+	TableInterface []struct {
+		RowInterface []Transceiver `json:"ROW_interface"`
+	} `json:"TABLE_interface"`
+}
+
+// GetTransceivers issues "show interface transceiver details" and
+// returns the parsed transceiver table.
+func (cli *Client) GetTransceivers() ([]Transceiver, error) {
+	body := &transceiverBody{}
+	if err := cli.run("show interface transceiver details", body); err != nil {
+		return nil, err
+	}
+	var transceivers []Transceiver
+	for _, table := range body.TableInterface {
+		transceivers = append(transceivers, table.RowInterface...)
+	}
+	return transceivers, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "encoding/json"
+
+// Transport fetches the result of a single show command and returns it
+// as raw JSON shaped like the body of an ins_api response for that
+// command, so every Get* method can unmarshal it the same way
+// regardless of which Transport produced it. The default Transport
+// speaks NX-API HTTP/JSON; the gnmi package's Transport maps the same
+// commands onto gNMI Get/Subscribe RPCs instead.
+type Transport interface {
+	Execute(cmd string) (json.RawMessage, error)
+}
+
+// Option configures a Client at construction time.
+type Option func(*Client)
+
+// WithTransport overrides the Client's Transport. Defaults to the
+// built-in NX-API HTTP/JSON transport when not supplied.
+func WithTransport(t Transport) Option {
+	return func(cli *Client) { cli.transport = t }
+}
+
+// RESTTransport returns a Transport that always speaks NX-API HTTP/JSON
+// against cli's connection settings (host, port, credentials), no
+// matter what cli's own Transport is currently set to. Hybrid Transports
+// such as the gnmi package's Transport use it as a fallback for commands
+// they don't map onto structured paths themselves.
+func (cli *Client) RESTTransport() Transport {
+	return &restTransport{cli: cli}
+}
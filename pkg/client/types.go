@@ -0,0 +1,82 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "encoding/json"
+
+// InsAPIRequest is the request envelope used by the classic NX-API
+// "ins_api" endpoint.
+type InsAPIRequest struct {
+	Params InsAPIParams `json:"ins_api"`
+}
+
+// InsAPIParams carries the parameters of an InsAPIRequest.
+type InsAPIParams struct {
+	Version      string `json:"version"`
+	Type         string `json:"type"`
+	Chunk        string `json:"chunk"`
+	Sid          string `json:"sid"`
+	Input        string `json:"input"`
+	OutputFormat string `json:"output_format"`
+}
+
+// InsAPIResponse is the response envelope returned by the "ins_api"
+// endpoint.
+type InsAPIResponse struct {
+	InsAPI struct {
+		Outputs struct {
+			Output InsAPIOutput `json:"output"`
+		} `json:"outputs"`
+		Sid     string `json:"sid"`
+		Type    string `json:"type"`
+		Version string `json:"version"`
+	} `json:"ins_api"`
+}
+
+// InsAPIOutput is a single command's result within an InsAPIResponse.
+type InsAPIOutput struct {
+	Body  json.RawMessage `json:"body"`
+	Code  string          `json:"code"`
+	Input string          `json:"input"`
+	Msg   string          `json:"msg"`
+}
+
+// JSONRPCRequest is a single entry in a JSON-RPC "cli" batch request.
+type JSONRPCRequest struct {
+	Jsonrpc string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  JSONRPCParams `json:"params"`
+	ID      int           `json:"id"`
+}
+
+// JSONRPCParams carries the parameters of a JSONRPCRequest.
+type JSONRPCParams struct {
+	Command string `json:"cmd"`
+	Version int    `json:"version"`
+}
+
+// JSONRPCResponse is a single entry in a JSON-RPC "cli" batch response.
+type JSONRPCResponse struct {
+	ID      int             `json:"id"`
+	Jsonrpc string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+}
+
+// JSONRPCError is the error object of a JSONRPCResponse, when present.
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
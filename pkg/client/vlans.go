@@ -0,0 +1,42 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// Vlan is the parsed result of a single entry in "show vlan".
+type Vlan struct {
+	ID     int    `json:"vlanshowbr-vlanid"`
+	Name   string `json:"vlanshowbr-vlanname"`
+	State  string `json:"vlanshowbr-vlanstate"`
+	Shared string `json:"vlanshowbr-shutstate"`
+}
+
+type vlanBody struct {
+	TableVlanbrief []struct {
+		RowVlanbrief []Vlan `json:"ROW_vlanbrief"`
+	} `json:"TABLE_vlanbrief"`
+}
+
+// GetVlans issues "show vlan" and returns the parsed VLAN table.
+func (cli *Client) GetVlans() ([]Vlan, error) {
+	body := &vlanBody{}
+	if err := cli.run("show vlan", body); err != nil {
+		return nil, err
+	}
+	var vlans []Vlan
+	for _, table := range body.TableVlanbrief {
+		vlans = append(vlans, table.RowVlanbrief...)
+	}
+	return vlans, nil
+}
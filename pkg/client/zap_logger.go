@@ -0,0 +1,53 @@
+// Copyright 2018 Paul Greenberg (greenpau@outlook.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "go.uber.org/zap"
+
+// zapLogger adapts a *zap.SugaredLogger to the Logger interface. It is
+// the Logger returned by NewZapLogger, used whenever a caller wants
+// structured output without writing their own adapter.
+type zapLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+// NewZapLogger wraps base in a Logger. Passing nil uses
+// zap.NewProduction().
+func NewZapLogger(base *zap.Logger) (Logger, error) {
+	if base == nil {
+		var err error
+		base, err = zap.NewProduction()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &zapLogger{sugar: base.Sugar()}, nil
+}
+
+func (l *zapLogger) Debug(msg string, fields ...interface{}) {
+	l.sugar.Debugw(msg, fields...)
+}
+
+func (l *zapLogger) Info(msg string, fields ...interface{}) {
+	l.sugar.Infow(msg, fields...)
+}
+
+func (l *zapLogger) Error(msg string, fields ...interface{}) {
+	l.sugar.Errorw(msg, fields...)
+}
+
+func (l *zapLogger) With(fields ...interface{}) Logger {
+	return &zapLogger{sugar: l.sugar.With(fields...)}
+}